@@ -0,0 +1,94 @@
+package main
+
+import "sort"
+
+// IssueDiff captures how a single URL's findings changed between two scans.
+type IssueDiff struct {
+	URL            string   `json:"url"`
+	Added          []string `json:"added,omitempty"`
+	Removed        []string `json:"removed,omitempty"`
+	SeverityBefore string   `json:"severity_before,omitempty"`
+	SeverityAfter  string   `json:"severity_after,omitempty"`
+}
+
+// ReportDiff is the result of comparing two archived reports by URL.
+type ReportDiff struct {
+	ScanIDBefore string      `json:"scan_id_before"`
+	ScanIDAfter  string      `json:"scan_id_after"`
+	AddedURLs    []string    `json:"added_urls,omitempty"`
+	RemovedURLs  []string    `json:"removed_urls,omitempty"`
+	Changed      []IssueDiff `json:"changed,omitempty"`
+}
+
+// diffReports compares two reports by URL and reports endpoints that
+// appeared, disappeared, or changed severity/issues between them.
+func diffReports(before, after *Report) *ReportDiff {
+	byURLBefore := indexByURL(before.Results)
+	byURLAfter := indexByURL(after.Results)
+
+	d := &ReportDiff{ScanIDBefore: before.ScanID, ScanIDAfter: after.ScanID}
+
+	for url := range byURLAfter {
+		if _, ok := byURLBefore[url]; !ok {
+			d.AddedURLs = append(d.AddedURLs, url)
+		}
+	}
+	for url := range byURLBefore {
+		if _, ok := byURLAfter[url]; !ok {
+			d.RemovedURLs = append(d.RemovedURLs, url)
+		}
+	}
+	for url, rb := range byURLBefore {
+		ra, ok := byURLAfter[url]
+		if !ok {
+			continue
+		}
+		added, removed := diffIssues(rb.Issues, ra.Issues)
+		if len(added) > 0 || len(removed) > 0 || rb.Severity != ra.Severity {
+			d.Changed = append(d.Changed, IssueDiff{
+				URL:            url,
+				Added:          added,
+				Removed:        removed,
+				SeverityBefore: rb.Severity,
+				SeverityAfter:  ra.Severity,
+			})
+		}
+	}
+
+	sort.Strings(d.AddedURLs)
+	sort.Strings(d.RemovedURLs)
+	sort.Slice(d.Changed, func(i, j int) bool { return d.Changed[i].URL < d.Changed[j].URL })
+	return d
+}
+
+func indexByURL(results []ScanResult) map[string]ScanResult {
+	m := make(map[string]ScanResult, len(results))
+	for _, r := range results {
+		m[r.URL] = r
+	}
+	return m
+}
+
+func diffIssues(before, after []string) (added, removed []string) {
+	beforeSet := toSet(before)
+	afterSet := toSet(after)
+	for _, i := range after {
+		if !beforeSet[i] {
+			added = append(added, i)
+		}
+	}
+	for _, i := range before {
+		if !afterSet[i] {
+			removed = append(removed, i)
+		}
+	}
+	return added, removed
+}
+
+func toSet(xs []string) map[string]bool {
+	m := make(map[string]bool, len(xs))
+	for _, x := range xs {
+		m[x] = true
+	}
+	return m
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffIssues(t *testing.T) {
+	tests := []struct {
+		name          string
+		before, after []string
+		wantAdded     []string
+		wantRemoved   []string
+	}{
+		{name: "no change", before: []string{"missing-csp"}, after: []string{"missing-csp"}},
+		{
+			name:      "issue added",
+			before:    []string{"missing-csp"},
+			after:     []string{"missing-csp", "missing-hsts"},
+			wantAdded: []string{"missing-hsts"},
+		},
+		{
+			name:        "issue removed",
+			before:      []string{"missing-csp", "missing-hsts"},
+			after:       []string{"missing-csp"},
+			wantRemoved: []string{"missing-hsts"},
+		},
+		{
+			name:   "both empty",
+			before: nil,
+			after:  nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			added, removed := diffIssues(tt.before, tt.after)
+			if !reflect.DeepEqual(added, tt.wantAdded) {
+				t.Errorf("added = %v, want %v", added, tt.wantAdded)
+			}
+			if !reflect.DeepEqual(removed, tt.wantRemoved) {
+				t.Errorf("removed = %v, want %v", removed, tt.wantRemoved)
+			}
+		})
+	}
+}
+
+func TestDiffReports(t *testing.T) {
+	before := &Report{
+		ScanID: "scan-1",
+		Results: []ScanResult{
+			{URL: "https://a.example.com", Severity: "high", Issues: []string{"missing-csp"}},
+			{URL: "https://b.example.com", Severity: "info"},
+		},
+	}
+	after := &Report{
+		ScanID: "scan-2",
+		Results: []ScanResult{
+			{URL: "https://a.example.com", Severity: "medium", Issues: []string{"missing-hsts"}},
+			{URL: "https://c.example.com", Severity: "high"},
+		},
+	}
+
+	d := diffReports(before, after)
+
+	if d.ScanIDBefore != "scan-1" || d.ScanIDAfter != "scan-2" {
+		t.Errorf("scan ids = %q/%q, want scan-1/scan-2", d.ScanIDBefore, d.ScanIDAfter)
+	}
+	if !reflect.DeepEqual(d.AddedURLs, []string{"https://c.example.com"}) {
+		t.Errorf("added urls = %v", d.AddedURLs)
+	}
+	if !reflect.DeepEqual(d.RemovedURLs, []string{"https://b.example.com"}) {
+		t.Errorf("removed urls = %v", d.RemovedURLs)
+	}
+	if len(d.Changed) != 1 || d.Changed[0].URL != "https://a.example.com" {
+		t.Fatalf("changed = %v, want one entry for a.example.com", d.Changed)
+	}
+	got := d.Changed[0]
+	if got.SeverityBefore != "high" || got.SeverityAfter != "medium" {
+		t.Errorf("severity before/after = %s/%s", got.SeverityBefore, got.SeverityAfter)
+	}
+	if !reflect.DeepEqual(got.Added, []string{"missing-hsts"}) || !reflect.DeepEqual(got.Removed, []string{"missing-csp"}) {
+		t.Errorf("added/removed issues = %v/%v", got.Added, got.Removed)
+	}
+}
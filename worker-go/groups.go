@@ -0,0 +1,81 @@
+package main
+
+import (
+	"os"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// groupRule maps a URL glob pattern (where "*" matches any run of
+// characters, including "/") to a group label and default tags.
+type groupRule struct {
+	Pattern string   `yaml:"pattern"`
+	Group   string   `yaml:"group"`
+	Tags    []string `yaml:"tags"`
+	re      *regexp.Regexp
+}
+
+// groupConfig is the parsed contents of groups.yml.
+type groupConfig struct {
+	Rules []groupRule `yaml:"groups"`
+}
+
+// loadGroupConfig reads and compiles groups.yml. A missing file is not an
+// error: group/tag metadata then comes only from the scanner itself.
+func loadGroupConfig(path string) (*groupConfig, error) {
+	b, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &groupConfig{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var cfg groupConfig
+	if err := yaml.Unmarshal(b, &cfg); err != nil {
+		return nil, err
+	}
+	for i := range cfg.Rules {
+		cfg.Rules[i].re = globToRegexp(cfg.Rules[i].Pattern)
+	}
+	return &cfg, nil
+}
+
+func globToRegexp(pattern string) *regexp.Regexp {
+	escaped := regexp.QuoteMeta(pattern)
+	escaped = strings.ReplaceAll(escaped, `\*`, `.*`)
+	return regexp.MustCompile("^" + escaped + "$")
+}
+
+// match returns the group label and tags for the first rule whose pattern
+// matches url, or ("", nil) if nothing matches.
+func (c *groupConfig) match(url string) (string, []string) {
+	if c == nil {
+		return "", nil
+	}
+	for _, rule := range c.Rules {
+		if rule.re.MatchString(url) {
+			return rule.Group, rule.Tags
+		}
+	}
+	return "", nil
+}
+
+// enrich fills in Group/Tags for results that weren't tagged at scan time,
+// so report files produced before groups.yml existed remain compatible.
+func (c *groupConfig) enrich(results []ScanResult) {
+	if c == nil {
+		return
+	}
+	for i := range results {
+		group, tags := c.match(results[i].URL)
+		if results[i].Group == "" {
+			results[i].Group = group
+		}
+		if len(results[i].Tags) == 0 {
+			results[i].Tags = tags
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package main
+
+import "testing"
+
+func TestGroupConfigMatch(t *testing.T) {
+	cfg := &groupConfig{Rules: []groupRule{
+		{Pattern: "https://api.example.com/*", Group: "api", Tags: []string{"prod"}},
+		{Pattern: "https://*.internal.example.com/*", Group: "internal"},
+	}}
+	for i := range cfg.Rules {
+		cfg.Rules[i].re = globToRegexp(cfg.Rules[i].Pattern)
+	}
+
+	tests := []struct {
+		name      string
+		url       string
+		wantGroup string
+		wantTags  []string
+	}{
+		{name: "matches first rule", url: "https://api.example.com/v1/users", wantGroup: "api", wantTags: []string{"prod"}},
+		{name: "matches second rule", url: "https://svc.internal.example.com/health", wantGroup: "internal"},
+		{name: "no match", url: "https://unrelated.example.com/"},
+		{name: "nil config", url: "https://api.example.com/v1/users"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := cfg
+			if tt.name == "nil config" {
+				c = nil
+			}
+			group, tags := c.match(tt.url)
+			if group != tt.wantGroup {
+				t.Errorf("group = %q, want %q", group, tt.wantGroup)
+			}
+			if len(tags) != len(tt.wantTags) {
+				t.Errorf("tags = %v, want %v", tags, tt.wantTags)
+			}
+		})
+	}
+}
+
+func TestGroupConfigEnrich(t *testing.T) {
+	cfg := &groupConfig{Rules: []groupRule{
+		{Pattern: "https://api.example.com/*", Group: "api", Tags: []string{"prod"}},
+	}}
+	for i := range cfg.Rules {
+		cfg.Rules[i].re = globToRegexp(cfg.Rules[i].Pattern)
+	}
+
+	results := []ScanResult{
+		{URL: "https://api.example.com/v1/users"},
+		{URL: "https://api.example.com/v1/orders", Group: "already-tagged", Tags: []string{"custom"}},
+		{URL: "https://unrelated.example.com/"},
+	}
+	cfg.enrich(results)
+
+	if results[0].Group != "api" || len(results[0].Tags) != 1 || results[0].Tags[0] != "prod" {
+		t.Errorf("untagged result not enriched: %+v", results[0])
+	}
+	if results[1].Group != "already-tagged" || results[1].Tags[0] != "custom" {
+		t.Errorf("already-tagged result was overwritten: %+v", results[1])
+	}
+	if results[2].Group != "" {
+		t.Errorf("unmatched result got a group: %+v", results[2])
+	}
+}
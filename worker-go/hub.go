@@ -0,0 +1,146 @@
+package main
+
+import (
+	"log"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// eventType identifies the kind of message pushed to dashboard clients over /ws.
+type eventType string
+
+const (
+	eventScanStarted     eventType = "scan_started"
+	eventTargetCompleted eventType = "target_completed"
+	eventScanFinished    eventType = "scan_finished"
+	eventHeartbeat       eventType = "heartbeat"
+)
+
+// event is the JSON envelope written to every WebSocket client.
+type event struct {
+	Type    eventType `json:"type"`
+	Payload any       `json:"payload,omitempty"`
+}
+
+const (
+	clientSendBuffer = 16
+	writeWait        = 10 * time.Second
+	pongWait         = 60 * time.Second
+	pingPeriod       = (pongWait * 9) / 10
+	heartbeatEvery   = 15 * time.Second
+)
+
+// wsClient is a single connected dashboard subscriber. Outbound events are
+// queued on send; if a slow client can't keep up the hub drops it rather
+// than blocking the other subscribers.
+type wsClient struct {
+	conn *websocket.Conn
+	send chan event
+}
+
+// hub fans broadcast events out to every registered client and keeps the
+// client set consistent across concurrent register/unregister/broadcast.
+type hub struct {
+	register   chan *wsClient
+	unregister chan *wsClient
+	broadcast  chan event
+	clients    map[*wsClient]bool
+}
+
+func newHub() *hub {
+	return &hub{
+		register:   make(chan *wsClient),
+		unregister: make(chan *wsClient),
+		broadcast:  make(chan event, 16),
+		clients:    make(map[*wsClient]bool),
+	}
+}
+
+func (h *hub) run() {
+	for {
+		select {
+		case c := <-h.register:
+			h.clients[c] = true
+			log.Printf("[ws] client connected (total: %d)", len(h.clients))
+		case c := <-h.unregister:
+			if _, ok := h.clients[c]; ok {
+				delete(h.clients, c)
+				close(c.send)
+				log.Printf("[ws] client disconnected (total: %d)", len(h.clients))
+			}
+		case e := <-h.broadcast:
+			for c := range h.clients {
+				select {
+				case c.send <- e:
+				default:
+					log.Println("[ws] client send buffer full, dropping client")
+					delete(h.clients, c)
+					close(c.send)
+				}
+			}
+		}
+	}
+}
+
+// emit queues a typed event for broadcast to every connected client.
+func (h *hub) emit(t eventType, payload any) {
+	h.broadcast <- event{Type: t, Payload: payload}
+}
+
+// heartbeatLoop periodically emits a heartbeat event so clients can detect a
+// stale connection without relying on the browser's own reload timers.
+func (h *hub) heartbeatLoop() {
+	ticker := time.NewTicker(heartbeatEvery)
+	defer ticker.Stop()
+	for range ticker.C {
+		h.emit(eventHeartbeat, map[string]string{"timestamp": time.Now().UTC().Format(time.RFC3339)})
+	}
+}
+
+// writePump delivers queued events to the client and pings it on idle
+// periods; it is the only goroutine allowed to write to the connection.
+func (c *wsClient) writePump() {
+	ticker := time.NewTicker(pingPeriod)
+	defer func() {
+		ticker.Stop()
+		c.conn.Close()
+	}()
+	for {
+		select {
+		case e, ok := <-c.send:
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if !ok {
+				c.conn.WriteMessage(websocket.CloseMessage, []byte{})
+				return
+			}
+			if err := c.conn.WriteJSON(e); err != nil {
+				return
+			}
+		case <-ticker.C:
+			c.conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := c.conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// readPump drains control frames from the client so pong/close messages are
+// processed; dashboard clients don't send application data.
+func (c *wsClient) readPump(h *hub) {
+	defer func() {
+		h.unregister <- c
+		c.conn.Close()
+	}()
+	c.conn.SetReadDeadline(time.Now().Add(pongWait))
+	c.conn.SetPongHandler(func(string) error {
+		c.conn.SetReadDeadline(time.Now().Add(pongWait))
+		return nil
+	})
+	for {
+		if _, _, err := c.conn.ReadMessage(); err != nil {
+			break
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+// recv reads the next event off ch, failing the test if none arrives within
+// the timeout. ok mirrors the channel-closed result of a bare receive.
+func recv(t *testing.T, ch <-chan event, timeout time.Duration) (e event, ok bool) {
+	t.Helper()
+	select {
+	case e, ok = <-ch:
+		return e, ok
+	case <-time.After(timeout):
+		t.Fatal("timed out waiting for event")
+		return event{}, false
+	}
+}
+
+func TestHubRegisterAndBroadcast(t *testing.T) {
+	h := newHub()
+	go h.run()
+
+	c := &wsClient{send: make(chan event, clientSendBuffer)}
+	h.register <- c
+
+	h.emit(eventTargetCompleted, "first")
+	h.emit(eventScanFinished, nil)
+
+	e, ok := recv(t, c.send, time.Second)
+	if !ok || e.Type != eventTargetCompleted || e.Payload != "first" {
+		t.Fatalf("got %+v, ok=%v, want target_completed/first", e, ok)
+	}
+	e, ok = recv(t, c.send, time.Second)
+	if !ok || e.Type != eventScanFinished {
+		t.Fatalf("got %+v, ok=%v, want scan_finished", e, ok)
+	}
+}
+
+func TestHubUnregisterClosesSendChannel(t *testing.T) {
+	h := newHub()
+	go h.run()
+
+	c := &wsClient{send: make(chan event, clientSendBuffer)}
+	h.register <- c
+	h.unregister <- c
+
+	// Give the hub goroutine a chance to process the unregister before we
+	// assert on it, then confirm a broadcast after unregister isn't
+	// delivered to the now-closed channel.
+	time.Sleep(20 * time.Millisecond)
+	h.emit(eventHeartbeat, nil)
+
+	if _, ok := recv(t, c.send, 100*time.Millisecond); ok {
+		t.Fatal("unregistered client still received a broadcast")
+	}
+}
+
+func TestHubDropsSlowClientOnOverflow(t *testing.T) {
+	h := newHub()
+	go h.run()
+
+	fast := &wsClient{send: make(chan event, clientSendBuffer)}
+	slow := &wsClient{send: make(chan event, 1)}
+	h.register <- fast
+	h.register <- slow
+
+	for i := 0; i < clientSendBuffer; i++ {
+		h.emit(eventTargetCompleted, i)
+	}
+
+	for i := 0; i < clientSendBuffer; i++ {
+		e, ok := recv(t, fast.send, time.Second)
+		if !ok || e.Payload != i {
+			t.Fatalf("fast client event %d = %+v, ok=%v", i, e, ok)
+		}
+	}
+
+	// slow's one-deep buffer should have overflowed on the second emit,
+	// so the hub drops it and closes its send channel.
+	drained := false
+	for i := 0; i < clientSendBuffer+1; i++ {
+		if _, ok := recv(t, slow.send, time.Second); !ok {
+			drained = true
+			break
+		}
+	}
+	if !drained {
+		t.Fatal("slow client's send channel was never closed after overflow")
+	}
+}
@@ -2,15 +2,19 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
 	"html/template"
 	"log"
 	"net/http"
 	"os"
-	"sync"
+	"path/filepath"
+	"sort"
 	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/gorilla/websocket"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 type ScanResult struct {
@@ -22,6 +26,8 @@ type ScanResult struct {
 	Error           string                 `json:"error"`
 	ResponseTimeMs  float64                `json:"response_time_ms"`
 	Timestamp       string                 `json:"timestamp"`
+	Group           string                 `json:"group,omitempty"`
+	Tags            []string               `json:"tags,omitempty"`
 }
 
 type Report struct {
@@ -44,6 +50,55 @@ func loadReport(path string) (*Report, error) {
 	return &report, nil
 }
 
+// loadReportWithGroups loads a report and enriches any results that weren't
+// already tagged with group/tag metadata at scan time.
+func loadReportWithGroups(path string, groups *groupConfig) (*Report, error) {
+	report, err := loadReport(path)
+	if err != nil {
+		return nil, err
+	}
+	groups.enrich(report.Results)
+	return report, nil
+}
+
+// scanLookupStatus maps a ReportStore lookup error to an HTTP status: a
+// malformed scan ID is a client error, anything else (report not archived
+// yet, read failure) is treated as not-found.
+func scanLookupStatus(err error) int {
+	if errors.Is(err, errInvalidScanID) {
+		return http.StatusBadRequest
+	}
+	return http.StatusNotFound
+}
+
+// filterResults returns the subset of results matching group and/or tag
+// (both optional; empty string means "no filter").
+func filterResults(results []ScanResult, group, tag string) []ScanResult {
+	if group == "" && tag == "" {
+		return results
+	}
+	filtered := make([]ScanResult, 0, len(results))
+	for _, r := range results {
+		if group != "" && r.Group != group {
+			continue
+		}
+		if tag != "" {
+			found := false
+			for _, t := range r.Tags {
+				if t == tag {
+					found = true
+					break
+				}
+			}
+			if !found {
+				continue
+			}
+		}
+		filtered = append(filtered, r)
+	}
+	return filtered
+}
+
 func countSeverity(results []ScanResult, sev string) int {
 	c := 0
 	for _, r := range results {
@@ -54,53 +109,96 @@ func countSeverity(results []ScanResult, sev string) int {
 	return c
 }
 
-var upgrader = websocket.Upgrader{
-	CheckOrigin: func(r *http.Request) bool { return true },
+// resultGroup is a named bucket of results for the dashboard's grouped view.
+type resultGroup struct {
+	Name    string
+	Results []ScanResult
 }
 
-var (
-	clients   = make(map[*websocket.Conn]bool)
-	clientsMu sync.Mutex
-)
+// groupResults buckets results by their Group field, falling back to
+// "ungrouped" for results without one, sorted by group name.
+func groupResults(results []ScanResult) []resultGroup {
+	byName := make(map[string][]ScanResult)
+	var order []string
+	for _, r := range results {
+		name := r.Group
+		if name == "" {
+			name = "ungrouped"
+		}
+		if _, ok := byName[name]; !ok {
+			order = append(order, name)
+		}
+		byName[name] = append(byName[name], r)
+	}
+	sort.Strings(order)
 
-func addClient(c *websocket.Conn) {
-	clientsMu.Lock()
-	defer clientsMu.Unlock()
-	clients[c] = true
-	log.Printf("[ws] Client connected (total: %d)", len(clients))
+	groups := make([]resultGroup, 0, len(order))
+	for _, name := range order {
+		groups = append(groups, resultGroup{Name: name, Results: byName[name]})
+	}
+	return groups
+}
+
+var upgrader = websocket.Upgrader{
+	CheckOrigin: func(r *http.Request) bool { return true },
 }
 
-func removeClient(c *websocket.Conn) {
-	clientsMu.Lock()
-	defer clientsMu.Unlock()
-	delete(clients, c)
-	c.Close()
-	log.Printf("[ws] Client disconnected (total: %d)", len(clients))
+// reportWatcher turns successive report snapshots from a ReportSource into
+// incremental events: a scan_started event when a new scan ID appears, one
+// target_completed event per newly-appeared result, and a scan_finished
+// event once every target has reported in. It holds the "have we seen this
+// already" state so the same logic applies regardless of which source
+// produced the snapshot.
+type reportWatcher struct {
+	h       *hub
+	store   ReportStore
+	metrics *scanMetrics
+
+	knownScanID  string
+	knownResults int
+	archived     bool
 }
 
-func broadcast(msg string) {
-	clientsMu.Lock()
-	defer clientsMu.Unlock()
+func (w *reportWatcher) apply(report *Report) {
+	w.metrics.update(report)
+
+	if report.ScanID != w.knownScanID {
+		w.knownScanID = report.ScanID
+		w.knownResults = 0
+		w.archived = false
+		w.h.emit(eventScanStarted, map[string]any{
+			"scan_id":       report.ScanID,
+			"timestamp":     report.Timestamp,
+			"total_targets": report.TotalTargets,
+		})
+	}
+
+	if w.knownResults < len(report.Results) {
+		for _, res := range report.Results[w.knownResults:] {
+			w.h.emit(eventTargetCompleted, res)
+		}
+		w.knownResults = len(report.Results)
+	}
 
-	for c := range clients {
-		if err := c.WriteMessage(websocket.TextMessage, []byte(msg)); err != nil {
-			log.Println("[ws] write error:", err)
-			delete(clients, c)
-			c.Close()
+	if report.TotalTargets > 0 && w.knownResults >= report.TotalTargets {
+		w.h.emit(eventScanFinished, report)
+		if !w.archived {
+			w.archived = true
+			if err := w.store.Save(report); err != nil {
+				log.Println("[store] failed to archive report:", err)
+			}
 		}
 	}
 }
 
-func watchReport(path string) {
-	var last time.Time
+// watchReport runs source until it exits and reopens it after a short delay,
+// so a FIFO writer reconnecting or a watched file briefly disappearing
+// doesn't require restarting the dashboard.
+func watchReport(source ReportSource, h *hub, store ReportStore, metrics *scanMetrics) {
+	w := &reportWatcher{h: h, store: store, metrics: metrics}
 	for {
-		info, err := os.Stat(path)
-		if err == nil {
-			if info.ModTime().After(last) {
-				last = info.ModTime()
-				log.Println("[watch] Report updated, broadcasting reload")
-				broadcast("reload")
-			}
+		if err := source.Watch(w.apply); err != nil {
+			log.Println("[watch] report source error:", err)
 		}
 		time.Sleep(500 * time.Millisecond)
 	}
@@ -113,7 +211,37 @@ func main() {
 		reportPath = "./reports/report.json"
 	}
 
-	go watchReport(reportPath)
+	historyDir := os.Getenv("REPORT_HISTORY_DIR")
+	if historyDir == "" {
+		historyDir = filepath.Join(filepath.Dir(reportPath), "history")
+	}
+	store, err := newFSReportStore(historyDir)
+	if err != nil {
+		log.Fatalf("[store] cannot init report archive at %s: %v", historyDir, err)
+	}
+
+	groupsPath := os.Getenv("GROUPS_CONFIG")
+	if groupsPath == "" {
+		groupsPath = "./groups.yml"
+	}
+	groups, err := loadGroupConfig(groupsPath)
+	if err != nil {
+		log.Fatalf("[groups] cannot load %s: %v", groupsPath, err)
+	}
+	store.groups = groups
+
+	metricsReg := prometheus.NewRegistry()
+	metrics := newScanMetrics(metricsReg)
+
+	source, err := newReportSource(os.Getenv("REPORT_SOURCE"), reportPath, groups)
+	if err != nil {
+		log.Fatalf("[watch] %v", err)
+	}
+
+	h := newHub()
+	go h.run()
+	go h.heartbeatLoop()
+	go watchReport(source, h, store, metrics)
 
 	r := mux.NewRouter()
 
@@ -123,28 +251,79 @@ func main() {
 			log.Println("[ws] upgrade:", err)
 			return
 		}
-		addClient(conn)
+		c := &wsClient{conn: conn, send: make(chan event, clientSendBuffer)}
+		h.register <- c
 
-		for {
-			if _, _, err := conn.ReadMessage(); err != nil {
-				removeClient(conn)
-				break
-			}
-		}
+		go c.writePump()
+		c.readPump(h)
 	})
 
 	r.HandleFunc("/api/report", func(w http.ResponseWriter, req *http.Request) {
-		report, err := loadReport(reportPath)
+		report, err := loadReportWithGroups(reportPath, groups)
+		if err != nil {
+			http.Error(w, err.Error(), 500)
+			return
+		}
+		group := req.URL.Query().Get("group")
+		tag := req.URL.Query().Get("tag")
+		if group != "" || tag != "" {
+			filtered := *report
+			filtered.Results = filterResults(report.Results, group, tag)
+			report = &filtered
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(report)
+	})
+
+	r.HandleFunc("/api/reports", func(w http.ResponseWriter, req *http.Request) {
+		metas, err := store.List()
 		if err != nil {
 			http.Error(w, err.Error(), 500)
 			return
 		}
 		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(metas)
+	})
+
+	r.HandleFunc("/api/reports/{scan_id}", func(w http.ResponseWriter, req *http.Request) {
+		report, err := store.Get(mux.Vars(req)["scan_id"])
+		if err != nil {
+			http.Error(w, err.Error(), scanLookupStatus(err))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
 		json.NewEncoder(w).Encode(report)
 	})
 
+	r.HandleFunc("/api/reports/{a}/diff/{b}", func(w http.ResponseWriter, req *http.Request) {
+		vars := mux.Vars(req)
+		before, err := store.Get(vars["a"])
+		if err != nil {
+			http.Error(w, "scan "+vars["a"]+": "+err.Error(), scanLookupStatus(err))
+			return
+		}
+		after, err := store.Get(vars["b"])
+		if err != nil {
+			http.Error(w, "scan "+vars["b"]+": "+err.Error(), scanLookupStatus(err))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(diffReports(before, after))
+	})
+
+	r.Handle("/metrics", promhttp.HandlerFor(metricsReg, promhttp.HandlerOpts{}))
+	r.HandleFunc("/api/timeseries", timeseriesHandler(store))
+
 	tmpl := template.Must(template.New("dash").Funcs(template.FuncMap{
 		"countSeverity": countSeverity,
+		"groupResults":  groupResults,
+		"toJSON": func(v any) template.JS {
+			b, err := json.Marshal(v)
+			if err != nil {
+				return "null"
+			}
+			return template.JS(b)
+		},
 	}).Parse(`
 <!DOCTYPE html>
 <html>
@@ -178,6 +357,65 @@ h1 { color:#58a6ff; margin-bottom:10px; display:flex; align-items:center; gap:10
 	border-radius:8px;
 }
 
+.group-filter {
+	display:flex;
+	align-items:center;
+	gap:15px;
+	margin-bottom:15px;
+	font-size:14px;
+}
+
+.group-filter select, .group-filter input {
+	background:#0d1117;
+	color:#c9d1d9;
+	border:1px solid #30363d;
+	border-radius:6px;
+	padding:6px 10px;
+}
+
+.group-block {
+	margin-bottom:15px;
+	border:1px solid #30363d;
+	border-radius:8px;
+	overflow:hidden;
+}
+
+.group-block summary {
+	cursor:pointer;
+	padding:10px 15px;
+	background:#21262d;
+	font-weight:600;
+	list-style:none;
+}
+
+.group-summary {
+	font-weight:400;
+	color:#8b949e;
+	font-size:12px;
+	margin-left:10px;
+}
+
+.tab-bar {
+	display:flex;
+	gap:10px;
+	margin-bottom:20px;
+}
+
+.tab-btn {
+	background:#161b22;
+	border:1px solid #30363d;
+	color:#8b949e;
+	padding:8px 16px;
+	border-radius:6px;
+	cursor:pointer;
+	font-size:14px;
+}
+
+.tab-btn.active {
+	color:#58a6ff;
+	border-color:#58a6ff;
+}
+
 .scan-info {
 	display:flex;
 	gap:30px;
@@ -391,6 +629,32 @@ ul li:before {
 	</div>
 </div>
 
+<div class="tab-bar">
+	<button class="tab-btn active" onclick="switchTab('live', this)">Live</button>
+	<button class="tab-btn" onclick="switchTab('history', this)">History</button>
+</div>
+
+<div id="history-panel" class="card" style="display:none;">
+	<h2>Scan History</h2>
+	<canvas id="historyChart" height="80"></canvas>
+	<table>
+		<thead>
+			<tr><th>Scan ID</th><th>Timestamp</th><th>High</th><th>Medium</th><th>p95 (ms)</th><th></th></tr>
+		</thead>
+		<tbody id="history-tbody"></tbody>
+	</table>
+	<h2 style="margin-top:20px;">Compare Two Scans</h2>
+	<div style="display:flex; gap:10px; align-items:center; margin-bottom:15px;">
+		<select id="diff-a"></select>
+		<span>vs</span>
+		<select id="diff-b"></select>
+		<button class="tab-btn" onclick="loadDiff()">Diff</button>
+	</div>
+	<div id="diff-output"></div>
+</div>
+
+<div id="live-panel">
+
 {{ if .Report }}
 {{ $results := .Report.Results }}
 {{ $total := len $results }}
@@ -432,112 +696,253 @@ ul li:before {
 
 <div class="card">
 	<h2>Scan Results</h2>
-	<table>
-		<thead>
-			<tr>
-				<th>Endpoint</th>
-				<th>Status</th>
-				<th>Severity</th>
-				<th>Issues</th>
-				<th>Security Headers</th>
-			</tr>
-		</thead>
-		<tbody>
-		{{ range $results }}
-			<tr class="sev-{{ .Severity }}">
-				<td>
-					<strong>{{ .URL }}</strong>
-					{{ if .ResponseTimeMs }}
-					<div class="response-time">⚡ {{ printf "%.0f" .ResponseTimeMs }}ms</div>
-					{{ end }}
-				</td>
-				<td>
-					{{ if .Status }}
-						{{ .Status }}
-					{{ else }}
-						<span style="color:#8b949e;">N/A</span>
-					{{ end }}
-				</td>
-				<td>
-					<span class="badge badge-{{ .Severity }}">{{ .Severity }}</span>
-				</td>
-				<td>
-					{{ if .Issues }}
-						<ul>
-						{{ range .Issues }}
-							<li>{{ . }}</li>
-						{{ end }}
-						</ul>
-					{{ else }}
-						<span class="no-issues">✓ No issues detected</span>
-					{{ end }}
-				</td>
-				<td>
-					<pre>{{ range $k,$v := .SecurityHeaders }}{{ $k }}: {{ if $v }}{{ $v }}{{ else }}<span style="color:#8b949e;">missing</span>{{ end }}
+	<div class="group-filter">
+		<label>Group:
+			<select id="filter-group">
+				<option value="">All</option>
+				{{ range $g := groupResults $results }}<option value="{{ $g.Name }}">{{ $g.Name }}</option>{{ end }}
+			</select>
+		</label>
+		<label>Tag: <input id="filter-tag" type="text" placeholder="tag"></label>
+		<button class="tab-btn" onclick="applyFilter()">Filter</button>
+		<button class="tab-btn" onclick="clearFilter()">Clear</button>
+	</div>
+	<div id="groups-container">
+	{{ range $g := groupResults $results }}
+		<details class="group-block" open>
+			<summary>
+				{{ $g.Name }}
+				<span class="group-summary">
+					{{ len $g.Results }} endpoints ·
+					<span class="stat-high">{{ countSeverity $g.Results "high" }} high</span> ·
+					<span class="stat-medium">{{ countSeverity $g.Results "medium" }} medium</span>
+				</span>
+			</summary>
+			<table>
+				<thead>
+					<tr>
+						<th>Endpoint</th>
+						<th>Status</th>
+						<th>Severity</th>
+						<th>Issues</th>
+						<th>Security Headers</th>
+					</tr>
+				</thead>
+				<tbody>
+				{{ range $g.Results }}
+					<tr class="sev-{{ .Severity }}">
+						<td>
+							<strong>{{ .URL }}</strong>
+							{{ if .ResponseTimeMs }}
+							<div class="response-time">⚡ {{ printf "%.0f" .ResponseTimeMs }}ms</div>
+							{{ end }}
+						</td>
+						<td>
+							{{ if .Status }}
+								{{ .Status }}
+							{{ else }}
+								<span style="color:#8b949e;">N/A</span>
+							{{ end }}
+						</td>
+						<td>
+							<span class="badge badge-{{ .Severity }}">{{ .Severity }}</span>
+						</td>
+						<td>
+							{{ if .Issues }}
+								<ul>
+								{{ range .Issues }}
+									<li>{{ . }}</li>
+								{{ end }}
+								</ul>
+							{{ else }}
+								<span class="no-issues">✓ No issues detected</span>
+							{{ end }}
+						</td>
+						<td>
+							<pre>{{ range $k,$v := .SecurityHeaders }}{{ $k }}: {{ if $v }}{{ $v }}{{ else }}<span style="color:#8b949e;">missing</span>{{ end }}
 {{ end }}</pre>
-				</td>
-			</tr>
-		{{ end }}
-		</tbody>
-	</table>
+						</td>
+					</tr>
+				{{ end }}
+				</tbody>
+			</table>
+		</details>
+	{{ end }}
+	</div>
 </div>
 
 <script>
-const sevData = {
-	labels:["High","Medium","Info","Error"],
-	datasets:[{
-		data:[
-			{{ countSeverity $results "high" }},
-			{{ countSeverity $results "medium" }},
-			{{ countSeverity $results "info" }},
-			{{ countSeverity $results "error" }}
-		],
-		backgroundColor:["#f85149","#d29922","#58a6ff","#f85149"]
-	}]
-};
+let results = {{ toJSON $results }};
 
-new Chart(document.getElementById("sevChart"), {
+function severityCounts(rs) {
+	const c = {high:0, medium:0, info:0, error:0};
+	for (const r of rs) { if (c[r.severity] !== undefined) c[r.severity]++; }
+	return c;
+}
+
+const sevChart = new Chart(document.getElementById("sevChart"), {
 	type:"doughnut",
-	data:sevData,
-	options:{
-		plugins:{
-			legend:{ labels:{ color:"#c9d1d9" } }
-		}
-	}
+	data:{
+		labels:["High","Medium","Info","Error"],
+		datasets:[{ data:Object.values(severityCounts(results)), backgroundColor:["#f85149","#d29922","#58a6ff","#f85149"] }]
+	},
+	options:{ plugins:{ legend:{ labels:{ color:"#c9d1d9" } } } }
 });
 
-new Chart(document.getElementById("issueChart"), {
+const issueChart = new Chart(document.getElementById("issueChart"), {
 	type:"bar",
 	data:{
-		labels:[ {{ range $results }}"{{ .URL }}",{{ end }} ],
-		datasets:[{
-			label:"Issues Found",
-			data:[ {{ range $results }}{{ len .Issues }},{{ end }} ],
-			backgroundColor:"#58a6ff"
-		}]
+		labels: results.map(r => r.url),
+		datasets:[{ label:"Issues Found", data: results.map(r => (r.issues||[]).length), backgroundColor:"#58a6ff" }]
 	},
-	options:{ 
-		scales:{ 
+	options:{
+		scales:{
 			y:{ beginAtZero:true, ticks:{color:"#c9d1d9"}, grid:{color:"#30363d"} },
 			x:{ ticks:{color:"#c9d1d9"}, grid:{color:"#30363d"} }
 		},
-		plugins:{
-			legend:{ labels:{ color:"#c9d1d9" } }
-		}
+		plugins:{ legend:{ labels:{ color:"#c9d1d9" } } }
 	}
 });
 
-let ws = new WebSocket((location.protocol==="https:"?"wss://":"ws://")+location.host+"/ws");
-ws.onmessage = (msg) => { 
-	if(msg.data==="reload"){ 
-		console.log("New scan detected, reloading...");
-		location.reload(); 
-	} 
-};
-ws.onclose = () => {
-	console.log("WebSocket disconnected, attempting reconnect...");
-	setTimeout(() => location.reload(), 3000);
-};
+function escapeHtml(s) {
+	return String(s).replace(/[&<>"']/g, ch => ({"&":"&amp;","<":"&lt;",">":"&gt;",'"':"&quot;","'":"&#39;"}[ch]));
+}
+
+function rowHtml(r) {
+	const issuesHtml = (r.issues && r.issues.length)
+		? "<ul>" + r.issues.map(i => "<li>" + escapeHtml(i) + "</li>").join("") + "</ul>"
+		: '<span class="no-issues">✓ No issues detected</span>';
+	const headersHtml = Object.entries(r.security_headers || {})
+		.map(([k,v]) => escapeHtml(k) + ": " + (v ? escapeHtml(v) : '<span style="color:#8b949e;">missing</span>') + "\n")
+		.join("");
+	const respTime = r.response_time_ms ? '<div class="response-time">⚡ ' + Math.round(r.response_time_ms) + 'ms</div>' : "";
+	return '<tr class="sev-' + escapeHtml(r.severity) + '">' +
+		'<td><strong>' + escapeHtml(r.url) + '</strong>' + respTime + '</td>' +
+		'<td>' + (r.status ? r.status : '<span style="color:#8b949e;">N/A</span>') + '</td>' +
+		'<td><span class="badge badge-' + escapeHtml(r.severity) + '">' + escapeHtml(r.severity) + '</span></td>' +
+		'<td>' + issuesHtml + '</td>' +
+		'<td><pre>' + headersHtml + '</pre></td>' +
+		'</tr>';
+}
+
+let filterGroup = "";
+let filterTag = "";
+
+function groupBy(rs) {
+	const byName = {};
+	const order = [];
+	for (const r of rs) {
+		const name = r.group || "ungrouped";
+		if (!byName[name]) { byName[name] = []; order.push(name); }
+		byName[name].push(r);
+	}
+	order.sort();
+	return order.map(name => ({name, results: byName[name]}));
+}
+
+function filteredResults() {
+	return results.filter(r =>
+		(!filterGroup || r.group === filterGroup) &&
+		(!filterTag || (r.tags || []).includes(filterTag))
+	);
+}
+
+function groupHtml(g) {
+	const c = severityCounts(g.results);
+	return '<details class="group-block" open><summary>' + escapeHtml(g.name) +
+		' <span class="group-summary">' + g.results.length + ' endpoints · ' +
+		'<span class="stat-high">' + c.high + ' high</span> · ' +
+		'<span class="stat-medium">' + c.medium + ' medium</span></span></summary>' +
+		'<table><thead><tr><th>Endpoint</th><th>Status</th><th>Severity</th><th>Issues</th><th>Security Headers</th></tr></thead>' +
+		'<tbody>' + g.results.map(rowHtml).join("") + '</tbody></table></details>';
+}
+
+function refreshGroupOptions() {
+	const sel = document.getElementById("filter-group");
+	const current = sel.value;
+	const names = Array.from(new Set(results.map(r => r.group || "ungrouped"))).sort();
+	sel.innerHTML = '<option value="">All</option>' + names.map(n => '<option value="' + escapeHtml(n) + '">' + escapeHtml(n) + '</option>').join("");
+	sel.value = names.includes(current) ? current : "";
+}
+
+function redrawTable() {
+	refreshGroupOptions();
+	document.getElementById("groups-container").innerHTML = groupBy(filteredResults()).map(groupHtml).join("");
+}
+
+function applyFilter() {
+	filterGroup = document.getElementById("filter-group").value;
+	filterTag = document.getElementById("filter-tag").value.trim();
+	redrawTable();
+}
+
+function clearFilter() {
+	filterGroup = "";
+	filterTag = "";
+	document.getElementById("filter-tag").value = "";
+	redrawTable();
+}
+
+function redrawStats() {
+	const c = severityCounts(results);
+	document.querySelectorAll(".stats-grid .stat-value")[0].textContent = results.length;
+	document.querySelectorAll(".stats-grid .stat-value")[1].textContent = c.high;
+	document.querySelectorAll(".stats-grid .stat-value")[2].textContent = c.medium;
+	document.querySelectorAll(".stats-grid .stat-value")[3].textContent = c.info;
+	document.querySelectorAll(".stats-grid .stat-value")[4].textContent = c.error;
+}
+
+function redrawCharts() {
+	const c = severityCounts(results);
+	sevChart.data.datasets[0].data = Object.values(c);
+	sevChart.update();
+	issueChart.data.labels = results.map(r => r.url);
+	issueChart.data.datasets[0].data = results.map(r => (r.issues||[]).length);
+	issueChart.update();
+}
+
+function setIndicator(cls) {
+	const el = document.querySelector(".status-indicator");
+	if (el) el.className = "status-indicator " + cls;
+}
+
+function connect() {
+	const ws = new WebSocket((location.protocol==="https:"?"wss://":"ws://")+location.host+"/ws");
+	ws.onmessage = (msg) => {
+		const evt = JSON.parse(msg.data);
+		switch (evt.type) {
+		case "scan_started":
+			if (!document.querySelector("table")) {
+				// first transition out of the empty state needs the full layout
+				location.reload();
+				return;
+			}
+			results = [];
+			redrawTable();
+			redrawStats();
+			redrawCharts();
+			setIndicator("status-waiting");
+			document.querySelector(".scan-info-value").textContent = evt.payload.scan_id;
+			break;
+		case "target_completed":
+			results.push(evt.payload);
+			redrawTable();
+			redrawStats();
+			redrawCharts();
+			break;
+		case "scan_finished":
+			setIndicator("status-live");
+			break;
+		case "heartbeat":
+			break;
+		}
+	};
+	ws.onclose = () => {
+		console.log("WebSocket disconnected, attempting reconnect...");
+		setTimeout(connect, 3000);
+	};
+}
+connect();
 </script>
 
 {{ else }}
@@ -550,17 +955,91 @@ ws.onclose = () => {
 
 <script>
 let ws = new WebSocket((location.protocol==="https:"?"wss://":"ws://")+location.host+"/ws");
-ws.onmessage = () => location.reload();
+ws.onmessage = (msg) => {
+	const evt = JSON.parse(msg.data);
+	if (evt.type === "scan_started") location.reload();
+};
 </script>
 
 {{ end }}
 
+</div>
+
+<script>
+function switchTab(name, btn) {
+	document.getElementById("live-panel").style.display = name === "live" ? "" : "none";
+	document.getElementById("history-panel").style.display = name === "history" ? "" : "none";
+	document.querySelectorAll(".tab-btn").forEach(b => b.classList.remove("active"));
+	btn.classList.add("active");
+	if (name === "history") loadHistory();
+}
+
+let historyChart = null;
+
+async function loadHistory() {
+	const res = await fetch("/api/reports");
+	const metas = await res.json();
+
+	const tbody = document.getElementById("history-tbody");
+	tbody.innerHTML = metas.map(m =>
+		"<tr><td>" + escapeHtml(m.scan_id) + "</td><td>" + escapeHtml(m.timestamp) + "</td><td>" + m.high_count +
+		"</td><td>" + m.medium_count + "</td><td>" + m.p95_response_ms.toFixed(0) + "</td><td></td></tr>"
+	).join("");
+
+	for (const sel of [document.getElementById("diff-a"), document.getElementById("diff-b")]) {
+		sel.innerHTML = metas.map(m => "<option value=\"" + escapeHtml(m.scan_id) + "\">" + escapeHtml(m.scan_id) + " (" + escapeHtml(m.timestamp) + ")</option>").join("");
+	}
+
+	const ctx = document.getElementById("historyChart");
+	const chartData = {
+		labels: metas.map(m => m.timestamp),
+		datasets: [
+			{ label:"High", data: metas.map(m => m.high_count), borderColor:"#f85149", backgroundColor:"#f85149" },
+			{ label:"Medium", data: metas.map(m => m.medium_count), borderColor:"#d29922", backgroundColor:"#d29922" },
+			{ label:"p95 response (ms)", data: metas.map(m => m.p95_response_ms), borderColor:"#58a6ff", backgroundColor:"#58a6ff", yAxisID:"y1" }
+		]
+	};
+	if (historyChart) {
+		historyChart.data = chartData;
+		historyChart.update();
+		return;
+	}
+	historyChart = new Chart(ctx, {
+		type:"line",
+		data: chartData,
+		options:{
+			scales:{
+				y:{ beginAtZero:true, ticks:{color:"#c9d1d9"}, grid:{color:"#30363d"} },
+				y1:{ position:"right", beginAtZero:true, ticks:{color:"#c9d1d9"}, grid:{display:false} },
+				x:{ ticks:{color:"#c9d1d9"}, grid:{color:"#30363d"} }
+			},
+			plugins:{ legend:{ labels:{ color:"#c9d1d9" } } }
+		}
+	});
+}
+
+async function loadDiff() {
+	const a = document.getElementById("diff-a").value;
+	const b = document.getElementById("diff-b").value;
+	if (!a || !b) return;
+	const res = await fetch("/api/reports/" + encodeURIComponent(a) + "/diff/" + encodeURIComponent(b));
+	const d = await res.json();
+	const out = document.getElementById("diff-output");
+	const list = (title, urls) => urls && urls.length
+		? "<h3>" + title + "</h3><ul>" + urls.map(u => "<li>" + escapeHtml(u) + "</li>").join("") + "</ul>" : "";
+	const changed = d.changed && d.changed.length
+		? "<h3>Changed</h3><ul>" + d.changed.map(c =>
+			"<li>" + escapeHtml(c.url) + ": " + escapeHtml(c.severity_before) + " → " + escapeHtml(c.severity_after) + "</li>").join("") + "</ul>" : "";
+	out.innerHTML = list("Added", d.added_urls) + list("Removed", d.removed_urls) + changed;
+}
+</script>
+
 </body>
 </html>
 `))
 
 	r.HandleFunc("/", func(w http.ResponseWriter, req *http.Request) {
-		report, err := loadReport(reportPath)
+		report, err := loadReportWithGroups(reportPath, groups)
 		if err != nil {
 			report = nil
 		}
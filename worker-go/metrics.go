@@ -0,0 +1,64 @@
+package main
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// scanMetrics exposes the latest report as Prometheus collectors so the
+// dashboard doubles as a scrape target for Prometheus + Grafana.
+type scanMetrics struct {
+	targetsTotal   prometheus.Gauge
+	issuesTotal    *prometheus.GaugeVec
+	responseTimeMs *prometheus.GaugeVec
+	durationSecs   prometheus.Gauge
+	lastTimestamp  prometheus.Gauge
+}
+
+func newScanMetrics(reg prometheus.Registerer) *scanMetrics {
+	m := &scanMetrics{
+		targetsTotal: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "apisec_scan_targets_total",
+			Help: "Number of targets in the most recent scan report.",
+		}),
+		issuesTotal: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "apisec_scan_issues_total",
+			Help: "Number of scanned endpoints at each severity in the most recent report.",
+		}, []string{"severity"}),
+		responseTimeMs: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "apisec_scan_response_time_ms",
+			Help: "Response time in milliseconds for each scanned URL in the most recent report.",
+		}, []string{"url"}),
+		durationSecs: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "apisec_scan_duration_seconds",
+			Help: "Duration of the most recent scan, in seconds.",
+		}),
+		lastTimestamp: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "apisec_scan_last_timestamp",
+			Help: "Unix timestamp of the most recent scan report.",
+		}),
+	}
+	reg.MustRegister(m.targetsTotal, m.issuesTotal, m.responseTimeMs, m.durationSecs, m.lastTimestamp)
+	return m
+}
+
+// update refreshes every collector from the latest report. Called from
+// watchReport whenever the report file changes.
+func (m *scanMetrics) update(r *Report) {
+	m.targetsTotal.Set(float64(r.TotalTargets))
+	m.durationSecs.Set(r.DurationSeconds)
+	if ts, err := time.Parse(time.RFC3339, r.Timestamp); err == nil {
+		m.lastTimestamp.Set(float64(ts.Unix()))
+	}
+
+	m.issuesTotal.Reset()
+	for _, sev := range []string{"high", "medium", "info", "error"} {
+		m.issuesTotal.WithLabelValues(sev).Set(float64(countSeverity(r.Results, sev)))
+	}
+
+	m.responseTimeMs.Reset()
+	for _, res := range r.Results {
+		m.responseTimeMs.WithLabelValues(res.URL).Set(res.ResponseTimeMs)
+	}
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// ReportSource delivers report snapshots to a reportWatcher as they become
+// available. Watch blocks for as long as the source has data to offer,
+// invoking onReport once per snapshot, and returns when the source is
+// exhausted or hits an unrecoverable error; watchReport reopens it after a
+// short delay so a FIFO writer or watched file can come and go freely.
+type ReportSource interface {
+	Watch(onReport func(*Report)) error
+}
+
+// newReportSource builds the ReportSource selected by REPORT_SOURCE (default
+// "file"):
+//
+//   - file: fsnotify-watches reportPath and reloads the whole file on write.
+//   - fifo: opens reportPath as a named pipe and reads one JSON ScanResult
+//     per line, assembling them into an in-memory Report.
+//   - stdin: same line protocol as fifo, read from os.Stdin, for
+//     containerized setups that pipe results in rather than writing a file.
+func newReportSource(kind, reportPath string, groups *groupConfig) (ReportSource, error) {
+	switch kind {
+	case "", "file":
+		return &fileSource{path: reportPath, groups: groups}, nil
+	case "fifo":
+		return &streamSource{groups: groups, open: func() (io.ReadCloser, error) { return os.Open(reportPath) }}, nil
+	case "stdin":
+		return &streamSource{groups: groups, open: func() (io.ReadCloser, error) { return io.NopCloser(os.Stdin), nil }}, nil
+	default:
+		return nil, fmt.Errorf("unknown REPORT_SOURCE %q (want file, fifo, or stdin)", kind)
+	}
+}
+
+// fileSource watches a report JSON file with fsnotify and reloads it
+// wholesale on every write, replacing the 500ms os.Stat polling loop.
+type fileSource struct {
+	path   string
+	groups *groupConfig
+}
+
+func (s *fileSource) Watch(onReport func(*Report)) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+
+	dir := filepath.Dir(s.path)
+	if err := watcher.Add(dir); err != nil {
+		return err
+	}
+
+	if report, err := loadReportWithGroups(s.path, s.groups); err == nil {
+		onReport(report)
+	}
+
+	for {
+		select {
+		case ev, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if filepath.Clean(ev.Name) != filepath.Clean(s.path) || ev.Op&(fsnotify.Write|fsnotify.Create) == 0 {
+				continue
+			}
+			report, err := loadReportWithGroups(s.path, s.groups)
+			if err != nil {
+				log.Println("[watch] failed to load report:", err)
+				continue
+			}
+			onReport(report)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			log.Println("[watch] fsnotify error:", err)
+		}
+	}
+}
+
+// streamSource reads line-delimited JSON ScanResult events from a FIFO or
+// stdin and assembles them into an in-memory Report, so the scanner backend
+// can stream results without ever writing a full report file. Each line
+// pushes an updated snapshot to onReport; total_targets is only known once
+// the stream closes, so a final snapshot marks the scan complete.
+type streamSource struct {
+	groups *groupConfig
+	open   func() (io.ReadCloser, error)
+}
+
+func (s *streamSource) Watch(onReport func(*Report)) error {
+	f, err := s.open()
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	start := time.Now()
+	report := &Report{
+		ScanID:    fmt.Sprintf("stream-%d", start.Unix()),
+		Timestamp: start.UTC().Format(time.RFC3339),
+	}
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var res ScanResult
+		if err := json.Unmarshal(line, &res); err != nil {
+			log.Println("[stream] failed to decode line:", err)
+			continue
+		}
+		report.Results = append(report.Results, res)
+		s.groups.enrich(report.Results[len(report.Results)-1:])
+		report.DurationSeconds = time.Since(start).Seconds()
+		onReport(report)
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+
+	report.TotalTargets = len(report.Results)
+	onReport(report)
+	return nil
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestStreamSourceEnrichesResultsInPlace(t *testing.T) {
+	groups := &groupConfig{Rules: []groupRule{
+		{Pattern: "https://api.example.com/*", Group: "api", Tags: []string{"prod"}},
+	}}
+	for i := range groups.Rules {
+		groups.Rules[i].re = globToRegexp(groups.Rules[i].Pattern)
+	}
+
+	lines := `{"url":"https://api.example.com/v1/users","severity":"high"}
+{"url":"https://other.example.com/","severity":"info"}
+`
+	src := &streamSource{
+		groups: groups,
+		open:   func() (io.ReadCloser, error) { return io.NopCloser(strings.NewReader(lines)), nil },
+	}
+
+	var last *Report
+	if err := src.Watch(func(r *Report) { last = r }); err != nil {
+		t.Fatalf("Watch() error: %v", err)
+	}
+
+	if len(last.Results) != 2 {
+		t.Fatalf("got %d results, want 2", len(last.Results))
+	}
+	if got := last.Results[0]; got.Group != "api" || len(got.Tags) != 1 || got.Tags[0] != "prod" {
+		t.Errorf("matched result not enriched: %+v", got)
+	}
+	if got := last.Results[1]; got.Group != "" {
+		t.Errorf("unmatched result got a group: %+v", got)
+	}
+	if last.TotalTargets != 2 {
+		t.Errorf("TotalTargets = %d, want 2", last.TotalTargets)
+	}
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"sync"
+)
+
+// scanIDPattern restricts scan IDs to the charset used in filenames under
+// dir, so a scan_id lifted straight from a URL path segment or an ingested
+// report can't escape the store directory.
+var scanIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// errInvalidScanID is returned by fsReportStore when a scan ID doesn't match
+// scanIDPattern, so callers can tell "bad input" apart from "not found".
+var errInvalidScanID = errors.New("invalid scan id")
+
+// ReportMeta summarizes an archived report for the history list, without
+// requiring the full result set to be loaded.
+type ReportMeta struct {
+	ScanID        string  `json:"scan_id"`
+	Timestamp     string  `json:"timestamp"`
+	TotalTargets  int     `json:"total_targets"`
+	HighCount     int     `json:"high_count"`
+	MediumCount   int     `json:"medium_count"`
+	InfoCount     int     `json:"info_count"`
+	ErrorCount    int     `json:"error_count"`
+	P50ResponseMs float64 `json:"p50_response_ms"`
+	P95ResponseMs float64 `json:"p95_response_ms"`
+}
+
+// ReportStore persists completed reports and makes them available for the
+// dashboard's history and diff views.
+type ReportStore interface {
+	Save(r *Report) error
+	List() ([]ReportMeta, error)
+	Get(scanID string) (*Report, error)
+}
+
+// fsReportStore indexes one JSON file per scan, named by ScanID, under dir.
+type fsReportStore struct {
+	dir    string
+	groups *groupConfig
+	mu     sync.Mutex
+}
+
+func newFSReportStore(dir string) (*fsReportStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &fsReportStore{dir: dir}, nil
+}
+
+func (s *fsReportStore) path(scanID string) (string, error) {
+	if !scanIDPattern.MatchString(scanID) {
+		return "", errInvalidScanID
+	}
+	return filepath.Join(s.dir, scanID+".json"), nil
+}
+
+func (s *fsReportStore) Save(r *Report) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path, err := s.path(r.ScanID)
+	if err != nil {
+		return err
+	}
+
+	b, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, b, 0o644)
+}
+
+func (s *fsReportStore) List() ([]ReportMeta, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	metas := make([]ReportMeta, 0, len(entries))
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != ".json" {
+			continue
+		}
+		r, err := loadReportWithGroups(filepath.Join(s.dir, e.Name()), s.groups)
+		if err != nil {
+			continue
+		}
+		metas = append(metas, reportMeta(r))
+	}
+
+	sort.Slice(metas, func(i, j int) bool { return metas[i].Timestamp < metas[j].Timestamp })
+	return metas, nil
+}
+
+func (s *fsReportStore) Get(scanID string) (*Report, error) {
+	path, err := s.path(scanID)
+	if err != nil {
+		return nil, err
+	}
+	return loadReportWithGroups(path, s.groups)
+}
+
+func reportMeta(r *Report) ReportMeta {
+	times := make([]float64, 0, len(r.Results))
+	for _, res := range r.Results {
+		if res.ResponseTimeMs > 0 {
+			times = append(times, res.ResponseTimeMs)
+		}
+	}
+	return ReportMeta{
+		ScanID:        r.ScanID,
+		Timestamp:     r.Timestamp,
+		TotalTargets:  r.TotalTargets,
+		HighCount:     countSeverity(r.Results, "high"),
+		MediumCount:   countSeverity(r.Results, "medium"),
+		InfoCount:     countSeverity(r.Results, "info"),
+		ErrorCount:    countSeverity(r.Results, "error"),
+		P50ResponseMs: percentile(times, 50),
+		P95ResponseMs: percentile(times, 95),
+	}
+}
+
+func percentile(values []float64, p float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	idx := int(p / 100 * float64(len(sorted)-1))
+	return sorted[idx]
+}
@@ -0,0 +1,40 @@
+package main
+
+import "testing"
+
+func TestPercentile(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []float64
+		p      float64
+		want   float64
+	}{
+		{name: "empty", values: nil, p: 50, want: 0},
+		{name: "single value", values: []float64{42}, p: 95, want: 42},
+		{name: "p50 of sorted set", values: []float64{10, 20, 30, 40, 50}, p: 50, want: 30},
+		{name: "p95 of sorted set", values: []float64{10, 20, 30, 40, 50}, p: 95, want: 40},
+		{name: "unsorted input", values: []float64{50, 10, 30}, p: 50, want: 30},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := percentile(tt.values, tt.p); got != tt.want {
+				t.Errorf("percentile(%v, %v) = %v, want %v", tt.values, tt.p, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFSReportStorePathRejectsInvalidScanID(t *testing.T) {
+	s := &fsReportStore{dir: t.TempDir()}
+
+	for _, scanID := range []string{"../../etc/passwd", "a/b", "a b", ""} {
+		if _, err := s.path(scanID); err != errInvalidScanID {
+			t.Errorf("path(%q) err = %v, want errInvalidScanID", scanID, err)
+		}
+	}
+
+	if _, err := s.path("scan-2024-01-01_12"); err != nil {
+		t.Errorf("path() rejected a valid scan id: %v", err)
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// timeseriesMetrics are the metric names accepted by /api/timeseries,
+// matched against ReportMeta fields computed by the report store.
+var timeseriesMetrics = map[string]func(ReportMeta) float64{
+	"high_count":      func(m ReportMeta) float64 { return float64(m.HighCount) },
+	"medium_count":    func(m ReportMeta) float64 { return float64(m.MediumCount) },
+	"info_count":      func(m ReportMeta) float64 { return float64(m.InfoCount) },
+	"error_count":     func(m ReportMeta) float64 { return float64(m.ErrorCount) },
+	"total_targets":   func(m ReportMeta) float64 { return float64(m.TotalTargets) },
+	"p50_response_ms": func(m ReportMeta) float64 { return m.P50ResponseMs },
+	"p95_response_ms": func(m ReportMeta) float64 { return m.P95ResponseMs },
+}
+
+// timeseriesHandler serves /api/timeseries?metric=...&since=..., returning
+// historical values in the [value, timestamp_ms] pair shape expected by
+// Grafana's SimpleJSON/Infinity datasource.
+func timeseriesHandler(store ReportStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, req *http.Request) {
+		metric := req.URL.Query().Get("metric")
+		valueOf, ok := timeseriesMetrics[metric]
+		if !ok {
+			http.Error(w, "unknown metric: "+metric, 400)
+			return
+		}
+
+		var since time.Time
+		if s := req.URL.Query().Get("since"); s != "" {
+			parsed, err := time.Parse(time.RFC3339, s)
+			if err != nil {
+				http.Error(w, "invalid since: "+err.Error(), 400)
+				return
+			}
+			since = parsed
+		}
+
+		metas, err := store.List()
+		if err != nil {
+			http.Error(w, err.Error(), 500)
+			return
+		}
+
+		datapoints := make([][2]float64, 0, len(metas))
+		for _, m := range metas {
+			ts, err := time.Parse(time.RFC3339, m.Timestamp)
+			if err != nil || ts.Before(since) {
+				continue
+			}
+			datapoints = append(datapoints, [2]float64{valueOf(m), float64(ts.UnixMilli())})
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{
+			"target":     metric,
+			"datapoints": datapoints,
+		})
+	}
+}